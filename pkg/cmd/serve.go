@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/nikoksr/proji/pkg/proji/storage/raftsqlite"
+	"github.com/spf13/cobra"
+)
+
+var (
+	serveNodeID    string
+	serveRaftAddr  string
+	serveHTTPAddr  string
+	serveDataDir   string
+	serveBootstrap bool
+	serveJoinAddr  string
+	serveAuthToken string
+)
+
+func init() {
+	serveCmd.Flags().StringVar(&serveNodeID, "node-id", "", "unique id of this node in the raft cluster")
+	serveCmd.Flags().StringVar(&serveRaftAddr, "raft-addr", "127.0.0.1:7000", "bind address for raft cluster traffic")
+	serveCmd.Flags().StringVar(&serveHTTPAddr, "http-addr", "127.0.0.1:8080", "bind address for the client-facing storage API")
+	serveCmd.Flags().StringVar(&serveDataDir, "data-dir", "./data", "directory the node stores its raft log and sqlite file in")
+	serveCmd.Flags().BoolVar(&serveBootstrap, "bootstrap", false, "bootstrap a new cluster with this node as the only voter")
+	serveCmd.Flags().StringVar(&serveJoinAddr, "join", "", "http address of an existing node to join through, e.g. http://host:8080")
+	serveCmd.Flags().StringVar(&serveAuthToken, "auth-token", "", "shared secret required to join the cluster or write to the catalog (also read from PROJI_SERVE_AUTH_TOKEN); leave unset only on a loopback/VPN-only deployment")
+	rootCmd.AddCommand(serveCmd)
+}
+
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run a raft-replicated proji storage node so a team can share a live class catalog",
+	Long: `Run a raft-replicated proji storage node so a team can share a live class catalog.
+
+The client-facing HTTP API (--http-addr) has no authentication unless
+--auth-token is set: anyone who can reach it can join the Raft cluster as a
+voter or write to the catalog. Only run without a token behind a loopback
+interface or a VPN/private network you already trust.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if serveAuthToken == "" {
+			serveAuthToken = os.Getenv("PROJI_SERVE_AUTH_TOKEN")
+		}
+
+		svc, err := raftsqlite.Open(raftsqlite.Config{
+			NodeID:       serveNodeID,
+			RaftBindAddr: serveRaftAddr,
+			DataDir:      serveDataDir,
+			Bootstrap:    serveBootstrap,
+		})
+		if err != nil {
+			return err
+		}
+		defer svc.Close()
+
+		if serveJoinAddr != "" {
+			if err := requestJoin(serveJoinAddr, serveNodeID, serveRaftAddr, serveAuthToken); err != nil {
+				return fmt.Errorf("joining cluster through %s: %w", serveJoinAddr, err)
+			}
+		}
+
+		server := raftsqlite.NewServer(svc, raftsqlite.WithAuthToken(serveAuthToken))
+		fmt.Printf("proji storage node %q listening for clients on %s (raft on %s)\n", serveNodeID, serveHTTPAddr, serveRaftAddr)
+		return http.ListenAndServe(serveHTTPAddr, server.Handler())
+	},
+}
+
+// requestJoin asks the node at joinAddr - presumably the current leader -
+// to add this node as a voter.
+func requestJoin(joinAddr, nodeID, raftAddr, authToken string) error {
+	body, err := json.Marshal(map[string]string{"node_id": nodeID, "addr": raftAddr})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, joinAddr+"/join", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+authToken)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("join request failed with status %d", resp.StatusCode)
+	}
+	return nil
+}
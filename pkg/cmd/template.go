@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nikoksr/proji/pkg/proji/template"
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	templateCmd.AddCommand(templateLsCmd)
+	rootCmd.AddCommand(templateCmd)
+}
+
+var templateCmd = &cobra.Command{
+	Use:   "template",
+	Short: "Manage builtin class file templates",
+	Long: `Manage builtin class file templates.
+
+A class_file's template column can reference one of these by its
+builtin:// URI instead of carrying a literal template string. That
+reference is not resolved anywhere yet - whatever writes a class's files
+and folders to disk during install still needs to call
+pkg/proji/template.Resolve on it. Until that's wired up, a class saved
+with a builtin:// template won't get the file it asked for.`,
+}
+
+var templateLsCmd = &cobra.Command{
+	Use:   "ls",
+	Short: "List the builtin templates available to class files",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		uris, err := template.List()
+		if err != nil {
+			return err
+		}
+
+		for _, uri := range uris {
+			fmt.Println(uri)
+		}
+		return nil
+	},
+}
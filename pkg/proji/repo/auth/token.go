@@ -0,0 +1,88 @@
+// Package auth resolves per-host API tokens for the repo importers, so a
+// self-hosted GitLab/Gitea instance can use different credentials than the
+// public host.
+package auth
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// providerConfig is one provider's section of the proji config file: a
+// default token plus optional per-host overrides.
+type providerConfig struct {
+	Token  string            `json:"token"`
+	Tokens map[string]string `json:"tokens"`
+}
+
+type fileConfig struct {
+	Gitlab providerConfig `json:"gitlab"`
+	Gitea  providerConfig `json:"gitea"`
+}
+
+// Token resolves the API token to use for provider ("gitlab" or "gitea")
+// against host, checking in order:
+//  1. PROJI_<PROVIDER>_TOKEN_<HOST>, a per-host env var
+//  2. PROJI_<PROVIDER>_TOKEN, a provider-wide env var
+//  3. the matching provider's "tokens" entry for host in
+//     $XDG_CONFIG_HOME/proji/config.json
+//  4. that provider's "token" default in the same file
+func Token(provider, host string) string {
+	envPrefix := "PROJI_" + strings.ToUpper(provider) + "_TOKEN"
+	if host != "" {
+		if token := os.Getenv(envPrefix + "_" + envHostSuffix(host)); token != "" {
+			return token
+		}
+	}
+	if token := os.Getenv(envPrefix); token != "" {
+		return token
+	}
+
+	pc := providerConfigFor(provider)
+	if host != "" {
+		if token, ok := pc.Tokens[host]; ok && token != "" {
+			return token
+		}
+	}
+	return pc.Token
+}
+
+func envHostSuffix(host string) string {
+	return strings.ToUpper(strings.NewReplacer(".", "_", "-", "_").Replace(host))
+}
+
+func providerConfigFor(provider string) providerConfig {
+	cfg, err := loadFileConfig()
+	if err != nil {
+		return providerConfig{}
+	}
+
+	switch provider {
+	case "gitlab":
+		return cfg.Gitlab
+	case "gitea":
+		return cfg.Gitea
+	default:
+		return providerConfig{}
+	}
+}
+
+func loadFileConfig() (fileConfig, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return fileConfig{}, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(configDir, "proji", "config.json"))
+	if err != nil {
+		return fileConfig{}, err
+	}
+
+	var cfg fileConfig
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return fileConfig{}, err
+	}
+	return cfg, nil
+}
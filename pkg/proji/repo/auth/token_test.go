@@ -0,0 +1,79 @@
+package auth
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withConfigFile points $XDG_CONFIG_HOME at a fresh temp dir containing the
+// given proji/config.json content, restoring the previous environment on
+// cleanup.
+func withConfigFile(t *testing.T, cfg fileConfig) {
+	t.Helper()
+
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "proji"), 0o755); err != nil {
+		t.Fatalf("mkdir config dir: %v", err)
+	}
+
+	data, err := json.Marshal(cfg)
+	if err != nil {
+		t.Fatalf("marshal config: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "proji", "config.json"), data, 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+
+	t.Setenv("XDG_CONFIG_HOME", dir)
+}
+
+func TestToken_EnvTakesPrecedenceOverFile(t *testing.T) {
+	withConfigFile(t, fileConfig{Gitlab: providerConfig{Token: "from-file"}})
+	t.Setenv("PROJI_GITLAB_TOKEN", "from-env")
+
+	if got := Token("gitlab", "gitlab.com"); got != "from-env" {
+		t.Errorf("Token() = %q, want %q", got, "from-env")
+	}
+}
+
+func TestToken_PerHostEnvTakesPrecedenceOverGenericEnv(t *testing.T) {
+	t.Setenv("PROJI_GITLAB_TOKEN", "generic")
+	t.Setenv("PROJI_GITLAB_TOKEN_GIT_EXAMPLE_COM", "per-host")
+
+	if got := Token("gitlab", "git.example.com"); got != "per-host" {
+		t.Errorf("Token() = %q, want %q", got, "per-host")
+	}
+}
+
+func TestToken_FilePerHostTakesPrecedenceOverFileDefault(t *testing.T) {
+	withConfigFile(t, fileConfig{Gitlab: providerConfig{
+		Token:  "default",
+		Tokens: map[string]string{"git.example.com": "per-host-file"},
+	}})
+
+	if got := Token("gitlab", "git.example.com"); got != "per-host-file" {
+		t.Errorf("Token() = %q, want %q", got, "per-host-file")
+	}
+	if got := Token("gitlab", "gitlab.com"); got != "default" {
+		t.Errorf("Token() = %q, want %q", got, "default")
+	}
+}
+
+func TestToken_NoneConfigured(t *testing.T) {
+	if got := Token("gitlab", "gitlab.com"); got != "" {
+		t.Errorf("Token() = %q, want empty string", got)
+	}
+}
+
+func TestToken_ProviderIsolation(t *testing.T) {
+	withConfigFile(t, fileConfig{Gitea: providerConfig{Token: "gitea-token"}})
+
+	if got := Token("gitlab", "gitlab.com"); got != "" {
+		t.Errorf("Token(\"gitlab\", ...) = %q, want empty string; gitea config must not leak into gitlab", got)
+	}
+	if got := Token("gitea", "gitea.example.com"); got != "gitea-token" {
+		t.Errorf("Token(\"gitea\", ...) = %q, want %q", got, "gitea-token")
+	}
+}
@@ -0,0 +1,238 @@
+// Package git implements a repo.Importer that clones the target repository
+// directly with go-git instead of talking to a provider's REST API. That
+// makes it work against anything speaking the git protocol - self-hosted
+// GitLab/Gitea, Bitbucket, plain SSH remotes, private repos - not just
+// gitlab.com.
+package git
+
+import (
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	httptransport "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/plumbing/transport/ssh"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/nikoksr/proji/pkg/proji/repo"
+	"github.com/tidwall/gjson"
+)
+
+// git struct holds important data about a repo that gets imported by cloning
+// it directly rather than through a provider API.
+type git struct {
+	repoURL    string
+	userName   string
+	repoName   string
+	branchName string
+	auth       transport.AuthMethod
+	optErr     error
+}
+
+// Option configures a git importer returned by New.
+type Option func(*git)
+
+// WithBasicAuth authenticates clones with a username and password. Many
+// hosts also accept a personal access token as the password.
+func WithBasicAuth(username, password string) Option {
+	return func(g *git) {
+		g.auth = &httptransport.BasicAuth{Username: username, Password: password}
+	}
+}
+
+// WithSSHAuth authenticates clones with a private key loaded from keyPath,
+// e.g. ~/.ssh/id_rsa. An empty keyPath lets go-git fall back to the default
+// SSH agent/key discovery.
+func WithSSHAuth(user, keyPath, passphrase string) Option {
+	return func(g *git) {
+		if keyPath == "" {
+			agentAuth, err := ssh.NewSSHAgentAuth(user)
+			if err != nil {
+				g.optErr = fmt.Errorf("connecting to ssh agent: %w", err)
+				return
+			}
+			g.auth = agentAuth
+			return
+		}
+
+		keys, err := ssh.NewPublicKeysFromFile(user, keyPath, passphrase)
+		if err != nil {
+			g.optErr = fmt.Errorf("loading ssh key from %q: %w", keyPath, err)
+			return
+		}
+		g.auth = keys
+	}
+}
+
+// New creates a new git repo object for repoURL. A ref - branch, tag or
+// commit - can be appended after a '#', e.g. "https://host/user/repo#v1.2.3".
+// Without one the host's default branch (HEAD) is used.
+func New(repoURL string, opts ...Option) (repo.Importer, error) {
+	rawURL, ref := splitRef(repoURL)
+
+	userName, repoName, err := splitUserRepo(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &git{repoURL: rawURL, userName: userName, repoName: repoName, branchName: ref}
+	for _, opt := range opts {
+		opt(g)
+	}
+	if g.optErr != nil {
+		return nil, g.optErr
+	}
+
+	return g, nil
+}
+
+func splitRef(repoURL string) (string, string) {
+	if idx := strings.LastIndex(repoURL, "#"); idx != -1 {
+		return repoURL[:idx], repoURL[idx+1:]
+	}
+	return repoURL, ""
+}
+
+// splitUserRepo extracts the full namespace path of a repo - which may
+// include one or more subgroups, e.g. "group/subgroup/repo", the same way
+// gitlab.repoPattern does, since self-hosted GitLab/Gitea instances this
+// package also targets route subgroups that way - off of either a regular
+// URL or git's SCP-like syntax (e.g. "git@host:group/repo.git").
+func splitUserRepo(rawURL string) (string, string, error) {
+	path, err := repoPathFromURL(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+
+	path = strings.TrimPrefix(strings.TrimSuffix(strings.TrimSuffix(path, "/"), ".git"), "/")
+	segments := strings.Split(path, "/")
+	userName := strings.Join(segments[:len(segments)-1], "/")
+	repoName := segments[len(segments)-1]
+
+	if userName == "" || repoName == "" {
+		return "", "", fmt.Errorf("could not extract user and/or repository name. Please check the URL")
+	}
+	return userName, repoName, nil
+}
+
+// repoPathFromURL returns the path portion of rawURL, i.e. everything after
+// the host: for a regular URL that's url.URL.Path, for git's SCP-like syntax
+// ("git@host:group/repo.git") it's everything after the first ':', since
+// net/url can't parse that form itself.
+func repoPathFromURL(rawURL string) (string, error) {
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return "", err
+		}
+		return u.Path, nil
+	}
+
+	if idx := strings.IndexByte(rawURL, ':'); idx != -1 && !strings.Contains(rawURL[:idx], "/") {
+		return rawURL[idx+1:], nil
+	}
+
+	return "", fmt.Errorf("could not extract user and/or repository name. Please check the URL")
+}
+
+// GetUserName returns the name of the repo owner
+func (g *git) GetUserName() string { return g.userName }
+
+// GetRepoName returns the name of the repo
+func (g *git) GetRepoName() string { return g.repoName }
+
+// GetBranchName returns the branch name, or "HEAD" if none was requested
+func (g *git) GetBranchName() string {
+	if g.branchName == "" {
+		return "HEAD"
+	}
+	return g.branchName
+}
+
+// GetTreePathsAndTypes clones the repo (bare, straight into memory, depth=1
+// unless a commit hash was requested) and walks the resulting commit tree to collect every entry's path and
+// type, mirroring what the provider-specific importers get back from their
+// tree APIs.
+func (g *git) GetTreePathsAndTypes() ([]gjson.Result, []gjson.Result, error) {
+	commit, err := g.cloneAndResolveCommit()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	paths := make([]gjson.Result, 0)
+	types := make([]gjson.Result, 0)
+
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+
+	for {
+		name, entry, err := walker.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+
+		entryType := "blob"
+		if entry.Mode == filemode.Dir {
+			entryType = "tree"
+		}
+		paths = append(paths, gjson.Parse(strconv.Quote(name)))
+		types = append(types, gjson.Parse(strconv.Quote(entryType)))
+	}
+
+	return paths, types, nil
+}
+
+var commitHashPattern = regexp.MustCompile(`^[0-9a-f]{7,40}$`)
+
+// cloneAndResolveCommit clones g.repoURL and returns the commit the
+// requested ref points at. A ref made of 7 to 40 lowercase hex digits is
+// taken as a raw commit hash (which forces a full, non-shallow clone since
+// go-git can't shallow-clone an arbitrary commit) and is never tried as a
+// branch or tag; any other ref is tried as a branch first, then as a tag.
+func (g *git) cloneAndResolveCommit() (*object.Commit, error) {
+	opts := &gogit.CloneOptions{URL: g.repoURL, Auth: g.auth, Depth: 1, SingleBranch: true}
+
+	switch {
+	case g.branchName == "":
+		// Default branch, nothing to do.
+	case commitHashPattern.MatchString(g.branchName):
+		opts.Depth = 0
+		opts.SingleBranch = false
+	default:
+		opts.ReferenceName = plumbing.NewBranchReferenceName(g.branchName)
+	}
+
+	repository, err := gogit.Clone(memory.NewStorage(), nil, opts)
+	if err != nil && opts.ReferenceName != "" {
+		opts.ReferenceName = plumbing.NewTagReferenceName(g.branchName)
+		repository, err = gogit.Clone(memory.NewStorage(), nil, opts)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if commitHashPattern.MatchString(g.branchName) {
+		return repository.CommitObject(plumbing.NewHash(g.branchName))
+	}
+
+	head, err := repository.Head()
+	if err != nil {
+		return nil, err
+	}
+	return repository.CommitObject(head.Hash())
+}
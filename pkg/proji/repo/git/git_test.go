@@ -0,0 +1,73 @@
+package git
+
+import "testing"
+
+func TestSplitUserRepo(t *testing.T) {
+	tests := []struct {
+		name         string
+		repoURL      string
+		wantUserName string
+		wantRepoName string
+		wantErr      bool
+	}{
+		{
+			name:         "https top-level",
+			repoURL:      "https://github.com/user/repo",
+			wantUserName: "user",
+			wantRepoName: "repo",
+		},
+		{
+			name:         "https with .git suffix",
+			repoURL:      "https://github.com/user/repo.git",
+			wantUserName: "user",
+			wantRepoName: "repo",
+		},
+		{
+			name:         "scp-like syntax",
+			repoURL:      "git@github.com:user/repo.git",
+			wantUserName: "user",
+			wantRepoName: "repo",
+		},
+		{
+			name:         "self-hosted with a subgroup",
+			repoURL:      "https://git.example.com/group/subgroup/repo",
+			wantUserName: "group/subgroup",
+			wantRepoName: "repo",
+		},
+		{
+			name:         "self-hosted with a subgroup and trailing slash",
+			repoURL:      "https://git.example.com/group/subgroup/repo.git/",
+			wantUserName: "group/subgroup",
+			wantRepoName: "repo",
+		},
+		{
+			name:         "scp-like syntax with a subgroup",
+			repoURL:      "git@git.example.com:group/subgroup/repo.git",
+			wantUserName: "group/subgroup",
+			wantRepoName: "repo",
+		},
+		{
+			name:    "no path",
+			repoURL: "https://github.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userName, repoName, err := splitUserRepo(tt.repoURL)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("splitUserRepo(%q): expected an error, got userName=%q repoName=%q", tt.repoURL, userName, repoName)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("splitUserRepo(%q): unexpected error: %v", tt.repoURL, err)
+			}
+			if userName != tt.wantUserName || repoName != tt.wantRepoName {
+				t.Errorf("splitUserRepo(%q) = (%q, %q), want (%q, %q)", tt.repoURL, userName, repoName, tt.wantUserName, tt.wantRepoName)
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+// Package gitea implements a repo.Importer for Gitea (and Forgejo) instances,
+// speaking their REST tree API the same way pkg/proji/repo/gitlab does for
+// GitLab.
+package gitea
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+
+	"github.com/nikoksr/proji/pkg/proji/repo"
+	"github.com/nikoksr/proji/pkg/proji/repo/auth"
+	"github.com/tidwall/gjson"
+)
+
+// gitea struct holds important data about a gitea repo
+type gitea struct {
+	apiBaseURI string
+	userName   string
+	repoName   string
+	branchName string
+	token      string
+}
+
+// Option configures a gitea importer returned by New.
+type Option func(*gitea)
+
+// WithAPIBaseURI points the importer at a specific Gitea instance instead of
+// sniffing one from the repo URL's host, e.g. "https://git.example.com/api/v1/repos/".
+func WithAPIBaseURI(apiBaseURI string) Option {
+	return func(g *gitea) { g.apiBaseURI = apiBaseURI }
+}
+
+// WithToken authenticates requests with a Gitea access token, sent as the
+// "Authorization: token <token>" header.
+func WithToken(token string) Option {
+	return func(g *gitea) { g.token = token }
+}
+
+// repoPattern extracts the owner and repo name, plus an optional branch name
+// off of a "/src/branch/<branch>" suffix, the Gitea equivalent of GitLab's
+// "/-/tree/<branch>".
+var repoPattern = regexp.MustCompile(`^/(?P<User>[^/]+)/(?P<Repo>[^/]+)(?:/src/branch/(?P<Branch>[^/]+))?/?$`)
+
+// New creates a new gitea repo object
+// Examples:
+//  - https://git.example.com/[user]/[repo]                            -> extracts user and repo name; no branch name
+//  - https://git.example.com/[user]/[repo]/src/branch/[develop]       -> extracts user, repo and branch name
+func New(repoURLPath string, opts ...Option) (repo.Importer, error) {
+	u, err := url.Parse(repoURLPath)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &gitea{}
+	if u.Host != "" {
+		g.apiBaseURI = u.Scheme + "://" + u.Host + "/api/v1/repos/"
+	}
+
+	specs := repoPattern.FindStringSubmatch(u.Path)
+	if specs == nil {
+		return nil, fmt.Errorf("could not extract user and/or repository name. Please check the URL")
+	}
+	userName := specs[1]
+	repoName := specs[2]
+	branchName := specs[3]
+
+	if userName == "" || repoName == "" {
+		return nil, fmt.Errorf("could not extract user and/or repository name. Please check the URL")
+	}
+
+	// Default to master if no branch was defined
+	if branchName == "" {
+		branchName = "master"
+	}
+
+	g.userName = userName
+	g.repoName = repoName
+	g.branchName = branchName
+	g.token = auth.Token("gitea", u.Host)
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	if g.apiBaseURI == "" {
+		return nil, fmt.Errorf("could not determine API base URI, please provide one with WithAPIBaseURI")
+	}
+
+	return g, nil
+}
+
+// GetUserName returns the name of the repo owner
+func (g *gitea) GetUserName() string { return g.userName }
+
+// GetRepoName returns the name of the repo
+func (g *gitea) GetRepoName() string { return g.repoName }
+
+// GetBranchName returns the branch name
+func (g *gitea) GetBranchName() string { return g.branchName }
+
+// GetTreePathsAndTypes gets the paths and types of the repo tree
+func (g *gitea) GetTreePathsAndTypes() ([]gjson.Result, []gjson.Result, error) {
+	paths := make([]gjson.Result, 0)
+	types := make([]gjson.Result, 0)
+	page := 1
+
+	for {
+		treeReq := fmt.Sprintf("%s%s/%s/git/trees/%s?recursive=true&per_page=100&page=%d", g.apiBaseURI, g.userName, g.repoName, g.branchName, page)
+
+		response, err := g.getRequest(treeReq)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		body, _ := ioutil.ReadAll(response.Body)
+		treeResponse := gjson.GetMany(string(body), "tree.#.path", "tree.#.type")
+		entries := treeResponse[0].Array()
+		paths = append(paths, entries...)
+		types = append(types, treeResponse[1].Array()...)
+		err = response.Body.Close()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		// Gitea's tree API signals truncation instead of a next-page header.
+		if !gjson.Get(string(body), "truncated").Bool() || len(entries) == 0 {
+			break
+		}
+		page++
+	}
+
+	return paths, types, nil
+}
+
+// getRequest wraps repo.GetRequest to attach an Authorization header when the
+// importer was configured with a token.
+func (g *gitea) getRequest(url string) (*http.Response, error) {
+	if g.token == "" {
+		return repo.GetRequest(url)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "token "+g.token)
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,66 @@
+package gitea
+
+import "testing"
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name           string
+		repoURLPath    string
+		wantUserName   string
+		wantRepoName   string
+		wantBranchName string
+		wantAPIBaseURI string
+	}{
+		{
+			name:           "no branch",
+			repoURLPath:    "https://git.example.com/user/repo",
+			wantUserName:   "user",
+			wantRepoName:   "repo",
+			wantBranchName: "master",
+			wantAPIBaseURI: "https://git.example.com/api/v1/repos/",
+		},
+		{
+			name:           "with branch",
+			repoURLPath:    "https://git.example.com/user/repo/src/branch/develop",
+			wantUserName:   "user",
+			wantRepoName:   "repo",
+			wantBranchName: "develop",
+		},
+		{
+			name:           "trailing slash",
+			repoURLPath:    "https://git.example.com/user/repo/",
+			wantUserName:   "user",
+			wantRepoName:   "repo",
+			wantBranchName: "master",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			importer, err := New(tt.repoURLPath)
+			if err != nil {
+				t.Fatalf("New(%q): unexpected error: %v", tt.repoURLPath, err)
+			}
+			g := importer.(*gitea)
+
+			if g.GetUserName() != tt.wantUserName {
+				t.Errorf("GetUserName() = %q, want %q", g.GetUserName(), tt.wantUserName)
+			}
+			if g.GetRepoName() != tt.wantRepoName {
+				t.Errorf("GetRepoName() = %q, want %q", g.GetRepoName(), tt.wantRepoName)
+			}
+			if g.GetBranchName() != tt.wantBranchName {
+				t.Errorf("GetBranchName() = %q, want %q", g.GetBranchName(), tt.wantBranchName)
+			}
+			if tt.wantAPIBaseURI != "" && g.apiBaseURI != tt.wantAPIBaseURI {
+				t.Errorf("apiBaseURI = %q, want %q", g.apiBaseURI, tt.wantAPIBaseURI)
+			}
+		})
+	}
+}
+
+func TestNew_MissingRepoName(t *testing.T) {
+	if _, err := New("https://git.example.com/user"); err == nil {
+		t.Fatal("expected an error for a URL missing the repo name")
+	}
+}
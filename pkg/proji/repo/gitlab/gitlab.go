@@ -3,9 +3,13 @@ package gitlab
 import (
 	"fmt"
 	"io/ioutil"
+	"net/http"
+	"net/url"
 	"regexp"
+	"strings"
 
 	"github.com/nikoksr/proji/pkg/proji/repo"
+	"github.com/nikoksr/proji/pkg/proji/repo/auth"
 	"github.com/tidwall/gjson"
 )
 
@@ -15,19 +19,56 @@ type gitlab struct {
 	userName   string
 	repoName   string
 	branchName string
+	token      string
 }
 
+// Option configures a gitlab importer returned by New.
+type Option func(*gitlab)
+
+// WithAPIBaseURI points the importer at a self-hosted GitLab API instead of
+// gitlab.com, e.g. "https://git.example.com/api/v4/projects/". Gitea instances
+// are served by pkg/proji/repo/gitea instead.
+func WithAPIBaseURI(apiBaseURI string) Option {
+	return func(g *gitlab) { g.apiBaseURI = apiBaseURI }
+}
+
+// WithToken authenticates requests with a GitLab personal/project access token,
+// sent as the "PRIVATE-TOKEN" header.
+func WithToken(token string) Option {
+	return func(g *gitlab) { g.token = token }
+}
+
+// repoPattern captures the full namespace path of a project - which may include
+// one or more subgroups, e.g. "group/subgroup/repo" - plus an optional branch
+// name off of a "/-/tree/<branch>" suffix.
+var repoPattern = regexp.MustCompile(`^/(?P<Path>.+?)(?:/-/tree/(?P<Branch>[^/]+))?/?$`)
+
 // New creates a new gitlab repo object
-func New(repoURLPath string) (repo.Importer, error) {
-	// Parse URL
-	// Examples:
-	//  - https://gitlab.com/[inkscape]/[inkscape]                  -> extracts user and repo name; no branch name
-	//  - https://gitlab.com/[inkscape]/[inkscape]/-/tree/[master]  -> extracts user, repo and branch name
-	r := regexp.MustCompile(`/(?P<User>[^/]+)/(?P<Repo>[^/]+)(/-/tree/(?P<Branch>[^/]+))?`)
-	specs := r.FindStringSubmatch(repoURLPath)
-	userName := specs[1]
-	repoName := specs[2]
-	branchName := specs[4]
+// Examples:
+//  - https://gitlab.com/[inkscape]/[inkscape]                           -> extracts user and repo name; no branch name
+//  - https://gitlab.com/[inkscape]/[inkscape]/-/tree/[master]           -> extracts user, repo and branch name
+//  - https://git.example.com/[group]/[subgroup]/[repo]/-/tree/[develop] -> self-hosted instance, subgroup aware
+func New(repoURLPath string, opts ...Option) (repo.Importer, error) {
+	g := &gitlab{apiBaseURI: "https://gitlab.com/api/v4/projects/"}
+
+	u, err := url.Parse(repoURLPath)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host != "" && u.Host != "gitlab.com" {
+		g.apiBaseURI = u.Scheme + "://" + u.Host + "/api/v4/projects/"
+	}
+
+	specs := repoPattern.FindStringSubmatch(u.Path)
+	if specs == nil {
+		return nil, fmt.Errorf("could not extract user and/or repository name. Please check the URL")
+	}
+	path := specs[1]
+	branchName := specs[2]
+
+	segments := strings.Split(path, "/")
+	userName := strings.Join(segments[:len(segments)-1], "/")
+	repoName := segments[len(segments)-1]
 
 	if userName == "" || repoName == "" {
 		return nil, fmt.Errorf("could not extract user and/or repository name. Please check the URL")
@@ -38,7 +79,16 @@ func New(repoURLPath string) (repo.Importer, error) {
 		branchName = "master"
 	}
 
-	return &gitlab{apiBaseURI: "https://gitlab.com/api/v4/projects/", userName: userName, repoName: repoName, branchName: branchName}, nil
+	g.userName = userName
+	g.repoName = repoName
+	g.branchName = branchName
+	g.token = auth.Token("gitlab", u.Host)
+
+	for _, opt := range opts {
+		opt(g)
+	}
+
+	return g, nil
 }
 
 // GetUserName returns the name of the repo owner
@@ -55,11 +105,12 @@ func (g *gitlab) GetTreePathsAndTypes() ([]gjson.Result, []gjson.Result, error)
 	nextPage := "1"
 	paths := make([]gjson.Result, 0)
 	types := make([]gjson.Result, 0)
-	treeReq := g.apiBaseURI + g.userName + "%2F" + g.repoName + "/repository/tree/?ref=" + g.branchName + "&recursive=true&per_page=100&page="
+	namespacedPath := strings.ReplaceAll(g.userName+"/"+g.repoName, "/", "%2F")
+	treeReq := g.apiBaseURI + namespacedPath + "/repository/tree/?ref=" + g.branchName + "&recursive=true&per_page=100&page="
 
 	for nextPage != "" {
 		// Request repo tree
-		response, err := repo.GetRequest(treeReq + nextPage)
+		response, err := g.getRequest(treeReq + nextPage)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -79,3 +130,19 @@ func (g *gitlab) GetTreePathsAndTypes() ([]gjson.Result, []gjson.Result, error)
 	}
 	return paths, types, nil
 }
+
+// getRequest wraps repo.GetRequest to attach a PRIVATE-TOKEN header when the
+// importer was configured with a token, which is required by self-hosted
+// instances and by private projects on gitlab.com alike.
+func (g *gitlab) getRequest(url string) (*http.Response, error) {
+	if g.token == "" {
+		return repo.GetRequest(url)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("PRIVATE-TOKEN", g.token)
+	return http.DefaultClient.Do(req)
+}
@@ -0,0 +1,68 @@
+package gitlab
+
+import "testing"
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name           string
+		repoURLPath    string
+		wantUserName   string
+		wantRepoName   string
+		wantBranchName string
+		wantAPIBaseURI string
+	}{
+		{
+			name:           "gitlab.com, no branch",
+			repoURLPath:    "https://gitlab.com/inkscape/inkscape",
+			wantUserName:   "inkscape",
+			wantRepoName:   "inkscape",
+			wantBranchName: "master",
+			wantAPIBaseURI: "https://gitlab.com/api/v4/projects/",
+		},
+		{
+			name:           "gitlab.com, with branch",
+			repoURLPath:    "https://gitlab.com/inkscape/inkscape/-/tree/master",
+			wantUserName:   "inkscape",
+			wantRepoName:   "inkscape",
+			wantBranchName: "master",
+		},
+		{
+			name:           "self-hosted, with a subgroup and branch",
+			repoURLPath:    "https://git.example.com/group/subgroup/repo/-/tree/develop",
+			wantUserName:   "group/subgroup",
+			wantRepoName:   "repo",
+			wantBranchName: "develop",
+			wantAPIBaseURI: "https://git.example.com/api/v4/projects/",
+		},
+		{
+			name:           "self-hosted, with nested subgroups",
+			repoURLPath:    "https://git.example.com/group/subgroup/subsubgroup/repo",
+			wantUserName:   "group/subgroup/subsubgroup",
+			wantRepoName:   "repo",
+			wantBranchName: "master",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			importer, err := New(tt.repoURLPath)
+			if err != nil {
+				t.Fatalf("New(%q): unexpected error: %v", tt.repoURLPath, err)
+			}
+			g := importer.(*gitlab)
+
+			if g.GetUserName() != tt.wantUserName {
+				t.Errorf("GetUserName() = %q, want %q", g.GetUserName(), tt.wantUserName)
+			}
+			if g.GetRepoName() != tt.wantRepoName {
+				t.Errorf("GetRepoName() = %q, want %q", g.GetRepoName(), tt.wantRepoName)
+			}
+			if g.GetBranchName() != tt.wantBranchName {
+				t.Errorf("GetBranchName() = %q, want %q", g.GetBranchName(), tt.wantBranchName)
+			}
+			if tt.wantAPIBaseURI != "" && g.apiBaseURI != tt.wantAPIBaseURI {
+				t.Errorf("apiBaseURI = %q, want %q", g.apiBaseURI, tt.wantAPIBaseURI)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package migrate
+
+import "database/sql"
+
+func init() {
+	Register(Migration{
+		Version: 1,
+		Name:    "initial_schema",
+		Up:      upInitialSchema,
+		Down:    downInitialSchema,
+	})
+}
+
+// upInitialSchema creates the tables proji has always shipped with, as
+// migration v1. Existing deployments predate the migrations table entirely,
+// so this runs against their tables too; every statement is written to be a
+// no-op there, which is what lets them upgrade cleanly.
+func upInitialSchema(tx *sql.Tx, dialect string) error {
+	pk := "INTEGER PRIMARY KEY AUTOINCREMENT"
+	// MySQL can't put a UNIQUE index on a TEXT column without a key length.
+	uniqueText := "TEXT"
+	switch dialect {
+	case "mysql":
+		pk = "INT AUTO_INCREMENT PRIMARY KEY"
+		uniqueText = "VARCHAR(255)"
+	case "postgres":
+		pk = "SERIAL PRIMARY KEY"
+	}
+
+	statements := []string{
+		"CREATE TABLE IF NOT EXISTS project_status (project_status_id " + pk + ", name " + uniqueText + " NOT NULL UNIQUE)",
+		"CREATE TABLE IF NOT EXISTS class (class_id " + pk + ", name " + uniqueText + " NOT NULL UNIQUE)",
+		"CREATE TABLE IF NOT EXISTS class_label (class_id INTEGER NOT NULL REFERENCES class(class_id), label TEXT NOT NULL)",
+		"CREATE TABLE IF NOT EXISTS class_folder (class_id INTEGER NOT NULL REFERENCES class(class_id), target TEXT NOT NULL, template TEXT)",
+		"CREATE TABLE IF NOT EXISTS class_file (class_id INTEGER NOT NULL REFERENCES class(class_id), target TEXT NOT NULL, template TEXT)",
+		"CREATE TABLE IF NOT EXISTS class_script (class_id INTEGER NOT NULL REFERENCES class(class_id), name TEXT NOT NULL, run_as_sudo BOOLEAN NOT NULL DEFAULT FALSE)",
+		"CREATE TABLE IF NOT EXISTS project (project_id " + pk + ", name TEXT NOT NULL, class_id INTEGER NOT NULL REFERENCES class(class_id), install_path " + uniqueText + " NOT NULL UNIQUE, install_date TIMESTAMP NOT NULL, project_status_id INTEGER NOT NULL REFERENCES project_status(project_status_id))",
+	}
+
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+
+	// The CREATE TABLEs above are idempotent, but this seed row isn't - an
+	// unconditional INSERT would fail its UNIQUE constraint on every
+	// deployment that already has an "active" status, which is every
+	// existing deployment this migration is meant to carry forward.
+	return seedProjectStatus(tx)
+}
+
+func seedProjectStatus(tx *sql.Tx) error {
+	var exists int
+	err := tx.QueryRow("SELECT COUNT(*) FROM project_status WHERE name = 'active'").Scan(&exists)
+	if err != nil {
+		return err
+	}
+	if exists > 0 {
+		return nil
+	}
+
+	_, err = tx.Exec("INSERT INTO project_status(name) VALUES ('active')")
+	return err
+}
+
+func downInitialSchema(tx *sql.Tx, dialect string) error {
+	tables := []string{"project", "class_script", "class_file", "class_folder", "class_label", "class", "project_status"}
+	for _, table := range tables {
+		if _, err := tx.Exec("DROP TABLE IF EXISTS " + table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
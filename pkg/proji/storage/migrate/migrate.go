@@ -0,0 +1,127 @@
+// Package migrate implements a minimal, dialect-aware schema migration
+// runner shared by proji's storage backends. Each backend's New calls
+// Migrate at startup so that upgrading proji never leaves an existing
+// deployment stuck on a schema the new code doesn't expect.
+package migrate
+
+import (
+	"database/sql"
+	"fmt"
+	"sort"
+)
+
+// Migration is a single, ordered schema change. Up must leave the database
+// at exactly Version; Down must undo it and leave the database at
+// Version-1. dialect is one of "sqlite", "mysql" or "postgres", for the rare
+// migration whose DDL isn't portable across all three.
+type Migration struct {
+	Version int
+	Name    string
+	Up      func(tx *sql.Tx, dialect string) error
+	Down    func(tx *sql.Tx, dialect string) error
+}
+
+var registry []Migration
+
+// Register adds a migration to the registry. It's called from init() in
+// each version's own file, so versions stay self-contained and the registry
+// doesn't need to be maintained by hand.
+func Register(m Migration) {
+	registry = append(registry, m)
+}
+
+// Migrate brings db up to the latest registered schema version.
+func Migrate(db *sql.DB, dialect string) error {
+	if err := ensureSchemaMigrationsTable(db, dialect); err != nil {
+		return err
+	}
+
+	current, err := currentVersion(db)
+	if err != nil {
+		return err
+	}
+
+	insert := fmt.Sprintf("INSERT INTO schema_migrations(version, name) VALUES (%s, %s)", placeholder(dialect, 1), placeholder(dialect, 2))
+
+	for _, m := range orderedMigrations() {
+		if m.Version <= current {
+			continue
+		}
+
+		tx, err := db.Begin()
+		if err != nil {
+			return err
+		}
+
+		if err := m.Up(tx, dialect); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s): %w", m.Version, m.Name, err)
+		}
+
+		if _, err := tx.Exec(insert, m.Version, m.Name); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("migration %d (%s): recording version: %w", m.Version, m.Name, err)
+		}
+
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Status reports the schema version db is currently at and how many
+// registered migrations are still pending, without applying them.
+func Status(db *sql.DB, dialect string) (current int, pending int, err error) {
+	if err = ensureSchemaMigrationsTable(db, dialect); err != nil {
+		return 0, 0, err
+	}
+
+	current, err = currentVersion(db)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	for _, m := range orderedMigrations() {
+		if m.Version > current {
+			pending++
+		}
+	}
+	return current, pending, nil
+}
+
+func ensureSchemaMigrationsTable(db *sql.DB, dialect string) error {
+	autoTimestamp := "CURRENT_TIMESTAMP"
+	if dialect == "postgres" {
+		autoTimestamp = "NOW()"
+	}
+	ddl := fmt.Sprintf(
+		"CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER PRIMARY KEY, name TEXT NOT NULL, applied_at TIMESTAMP NOT NULL DEFAULT %s)",
+		autoTimestamp,
+	)
+	_, err := db.Exec(ddl)
+	return err
+}
+
+func currentVersion(db *sql.DB) (int, error) {
+	var version sql.NullInt64
+	if err := db.QueryRow("SELECT MAX(version) FROM schema_migrations").Scan(&version); err != nil {
+		return 0, err
+	}
+	return int(version.Int64), nil
+}
+
+func orderedMigrations() []Migration {
+	ordered := make([]Migration, len(registry))
+	copy(ordered, registry)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].Version < ordered[j].Version })
+	return ordered
+}
+
+func placeholder(dialect string, n int) string {
+	if dialect == "postgres" {
+		return fmt.Sprintf("$%d", n)
+	}
+	return "?"
+}
@@ -0,0 +1,40 @@
+package migrate
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// TestMigrate_ExistingDeployment mimics a pre-migrations proji database:
+// the project_status table and its "active" row already exist, just like
+// in any real deployment upgrading into this package for the first time.
+// Migrate must apply v1 over it without failing on the seed row's UNIQUE
+// constraint and without adding a second "active" row.
+func TestMigrate_ExistingDeployment(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE project_status (project_status_id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT NOT NULL UNIQUE)"); err != nil {
+		t.Fatalf("seed project_status table: %v", err)
+	}
+	if _, err := db.Exec("INSERT INTO project_status(name) VALUES ('active')"); err != nil {
+		t.Fatalf("seed active status: %v", err)
+	}
+
+	if err := Migrate(db, "sqlite"); err != nil {
+		t.Fatalf("Migrate on pre-existing deployment: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM project_status WHERE name = 'active'").Scan(&count); err != nil {
+		t.Fatalf("count active statuses: %v", err)
+	}
+	if count != 1 {
+		t.Fatalf("expected exactly one 'active' project_status row, got %d", count)
+	}
+}
@@ -0,0 +1,445 @@
+// Package mysql implements storage.Service against a MySQL/MariaDB
+// database. It mirrors pkg/proji/storage/sqlite statement for statement;
+// only the driver and its error types differ.
+package mysql
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/nikoksr/proji/pkg/proji/storage"
+	"github.com/nikoksr/proji/pkg/proji/storage/migrate"
+)
+
+// Mysql represents a mysql connection.
+type mysql struct {
+	db *sql.DB
+	tx *sql.Tx
+}
+
+// New creates a new connection to a mysql database. dsn follows the
+// go-sql-driver/mysql DSN format, e.g. "user:pass@tcp(127.0.0.1:3306)/proji".
+func New(dsn string) (storage.Service, error) {
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// Verify connection
+	if err = db.Ping(); err != nil {
+		return nil, err
+	}
+
+	if err = migrate.Migrate(db, "mysql"); err != nil {
+		return nil, err
+	}
+
+	return &mysql{db, nil}, nil
+}
+
+func (s *mysql) Close() error {
+	return s.db.Close()
+}
+
+func (s *mysql) SaveClass(class *storage.Class) error {
+	if err := s.saveName(class.Name); err != nil {
+		return err
+	}
+
+	// After saving the name, the class gets a unique id.
+	id, err := s.LoadClassID(class.Name)
+	if err != nil {
+		if e := s.cancelSave(class.Name); e != nil {
+			return e
+		}
+		return err
+	}
+	class.ID = id
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	s.tx = tx
+
+	if err := s.saveLabels(class); err != nil {
+		if e := s.cancelSave(class.Name); e != nil {
+			return e
+		}
+		return err
+	}
+
+	if err := s.saveFolders(class); err != nil {
+		if e := s.cancelSave(class.Name); e != nil {
+			return e
+		}
+		return err
+	}
+
+	if err := s.saveFiles(class); err != nil {
+		if e := s.cancelSave(class.Name); e != nil {
+			return e
+		}
+		return err
+	}
+
+	if err := s.saveScripts(class); err != nil {
+		if e := s.cancelSave(class.Name); e != nil {
+			return e
+		}
+		return err
+	}
+
+	return s.tx.Commit()
+}
+
+func (s *mysql) cancelSave(className string) error {
+	if s.tx != nil {
+		if err := s.tx.Rollback(); err != nil {
+			return err
+		}
+	}
+	return s.RemoveClass(className)
+}
+
+func (s *mysql) saveName(name string) error {
+	query := "INSERT INTO class(name) VALUES(?)"
+	name = strings.ToLower(name)
+	_, err := s.db.Exec(query, name)
+	return err
+}
+
+func (s *mysql) saveLabels(class *storage.Class) error {
+	if s.tx == nil {
+		return fmt.Errorf("no open transaction")
+	}
+
+	query := "INSERT INTO class_label(class_id, label) VALUES(?, ?)"
+	stmt, err := s.tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, label := range class.Labels {
+		if _, err = stmt.Exec(class.ID, strings.ToLower(label)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mysql) saveFolders(class *storage.Class) error {
+	query := "INSERT INTO class_folder(class_id, target, template) VALUES(?, ?, ?)"
+	stmt, err := s.tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for target, template := range class.Folders {
+		if len(template) > 0 {
+			_, err = stmt.Exec(class.ID, target, template)
+		} else {
+			_, err = stmt.Exec(class.ID, target, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mysql) saveFiles(class *storage.Class) error {
+	query := "INSERT INTO class_file(class_id, target, template) VALUES(?, ?, ?)"
+	stmt, err := s.tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for target, template := range class.Files {
+		if len(template) > 0 {
+			_, err = stmt.Exec(class.ID, target, template)
+		} else {
+			_, err = stmt.Exec(class.ID, target, nil)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mysql) saveScripts(class *storage.Class) error {
+	query := "INSERT INTO class_script(class_id, name, run_as_sudo) VALUES(?, ?, ?)"
+	stmt, err := s.tx.Prepare(query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for script, asSudo := range class.Scripts {
+		if asSudo {
+			_, err = stmt.Exec(class.ID, script, 1)
+		} else {
+			_, err = stmt.Exec(class.ID, script, 0)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *mysql) LoadClassByName(name string) (*storage.Class, error) {
+	class := storage.NewClass(name)
+	var err error
+	class.ID, err = s.LoadClassID(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.loadLabels(class); err != nil {
+		return nil, err
+	}
+	if err := s.loadFolders(class); err != nil {
+		return nil, err
+	}
+	if err := s.loadFiles(class); err != nil {
+		return nil, err
+	}
+	return class, s.loadScripts(class)
+}
+
+func (s *mysql) LoadClassByID(id uint) (*storage.Class, error) {
+	class := storage.NewClass("")
+	class.ID = id
+
+	if err := s.loadName(class); err != nil {
+		return nil, err
+	}
+	if err := s.loadLabels(class); err != nil {
+		return nil, err
+	}
+	if err := s.loadFolders(class); err != nil {
+		return nil, err
+	}
+	if err := s.loadFiles(class); err != nil {
+		return nil, err
+	}
+	return class, s.loadScripts(class)
+}
+
+func (s *mysql) LoadClassID(name string) (uint, error) {
+	query := "SELECT class_id FROM class WHERE name = ?"
+
+	idRows, err := s.db.Query(query, name)
+	if err != nil {
+		return 0, err
+	}
+	defer idRows.Close()
+
+	if !idRows.Next() {
+		return 0, fmt.Errorf("could not find class %s in database", name)
+	}
+
+	var id uint
+	err = idRows.Scan(&id)
+	return id, err
+}
+
+func (s *mysql) LoadAllClasses() ([]*storage.Class, error) {
+	query := "SELECT name FROM class ORDER BY name"
+
+	classRows, err := s.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer classRows.Close()
+
+	var classes []*storage.Class
+
+	for classRows.Next() {
+		var name string
+		classRows.Scan(&name)
+		class, err := s.LoadClassByName(name)
+		if err != nil {
+			return nil, err
+		}
+		classes = append(classes, class)
+	}
+	return classes, nil
+}
+
+func (s *mysql) loadName(class *storage.Class) error {
+	query := "SELECT name FROM class WHERE class_id = ?"
+
+	nameRows, err := s.db.Query(query, class.ID)
+	if err != nil {
+		return err
+	}
+	defer nameRows.Close()
+
+	if !nameRows.Next() {
+		return fmt.Errorf("could not find class with id %d in database", class.ID)
+	}
+	return nameRows.Scan(&class.Name)
+}
+
+func (s *mysql) loadLabels(class *storage.Class) error {
+	query := "SELECT label FROM class_label WHERE class_id = ? ORDER BY label"
+
+	labelRows, err := s.db.Query(query, class.ID)
+	if err != nil {
+		return err
+	}
+	defer labelRows.Close()
+
+	for labelRows.Next() {
+		var label string
+		labelRows.Scan(&label)
+		class.Labels = append(class.Labels, label)
+	}
+	return nil
+}
+
+func (s *mysql) loadFolders(class *storage.Class) error {
+	query := "SELECT target, template FROM class_folder WHERE class_id = ? ORDER BY target"
+
+	folderRows, err := s.db.Query(query, class.ID)
+	if err != nil {
+		return err
+	}
+	defer folderRows.Close()
+
+	for folderRows.Next() {
+		var target, template string
+		folderRows.Scan(&target, &template)
+		class.Folders[target] = template
+	}
+	return nil
+}
+
+// loadFiles returns each class_file's template column as stored - still a
+// literal template or a "builtin://..." URI, whichever the class was saved
+// with. Resolving a builtin:// value through pkg/proji/template is not yet
+// wired into this class's file materialization and remains a required
+// follow-up, not something this storage layer does.
+func (s *mysql) loadFiles(class *storage.Class) error {
+	query := "SELECT target, template FROM class_file WHERE class_id = ? ORDER BY target"
+
+	fileRows, err := s.db.Query(query, class.ID)
+	if err != nil {
+		return err
+	}
+	defer fileRows.Close()
+
+	for fileRows.Next() {
+		var target, template string
+		fileRows.Scan(&target, &template)
+		class.Files[target] = template
+	}
+	return nil
+}
+
+func (s *mysql) loadScripts(class *storage.Class) error {
+	query := "SELECT name, run_as_sudo FROM class_script WHERE class_id = ? ORDER BY run_as_sudo, name"
+
+	scriptRows, err := s.db.Query(query, class.ID)
+	if err != nil {
+		return err
+	}
+	defer scriptRows.Close()
+
+	for scriptRows.Next() {
+		var scriptName string
+		var runAsSudo bool
+		scriptRows.Scan(&scriptName, &runAsSudo)
+		class.Scripts[scriptName] = runAsSudo
+	}
+	return nil
+}
+
+func (s *mysql) RemoveClass(name string) error {
+	var err error
+
+	classID, err := s.LoadClassID(name)
+	if err != nil {
+		return err
+	}
+
+	s.tx, err = s.db.Begin()
+	if err != nil {
+		return err
+	}
+
+	// Remove class and dependencies
+	if err = s.removeName(classID); err != nil {
+		return err
+	}
+	if err = s.removeLabels(classID); err != nil {
+		return err
+	}
+	if err = s.removeFolders(classID); err != nil {
+		return err
+	}
+	if err = s.removeFiles(classID); err != nil {
+		return err
+	}
+	if err = s.removeScripts(classID); err != nil {
+		return err
+	}
+	return s.tx.Commit()
+}
+
+func (s *mysql) removeName(classID uint) error {
+	_, err := s.tx.Exec("DELETE FROM class WHERE class_id = ?", classID)
+	return err
+}
+
+func (s *mysql) removeLabels(classID uint) error {
+	_, err := s.tx.Exec("DELETE FROM class_label WHERE class_id = ?", classID)
+	return err
+}
+
+func (s *mysql) removeFolders(classID uint) error {
+	_, err := s.tx.Exec("DELETE FROM class_folder WHERE class_id = ?", classID)
+	return err
+}
+
+func (s *mysql) removeFiles(classID uint) error {
+	_, err := s.tx.Exec("DELETE FROM class_file WHERE class_id = ?", classID)
+	return err
+}
+
+func (s *mysql) removeScripts(classID uint) error {
+	_, err := s.tx.Exec("DELETE FROM class_script WHERE class_id = ?", classID)
+	return err
+}
+
+func (s *mysql) DoesLabelExist(label string) (uint, error) {
+	query := "SELECT class_id FROM class_label WHERE label = ?"
+	var id uint
+	err := s.db.QueryRow(query, label).Scan(&id)
+	return id, err
+}
+
+func (s *mysql) TrackProject(proj *storage.Project) error {
+	t := time.Now().Local()
+	_, err := s.db.Exec(
+		"INSERT INTO project(name, class_id, install_path, install_date, project_status_id) VALUES(?, ?, ?, ?, ?)",
+		proj.Name,
+		proj.Class.ID,
+		proj.InstallPath,
+		t,
+		1,
+	)
+
+	if storage.IsUniqueConstraintError(err) {
+		return storage.ErrProjectExists
+	}
+	return err
+}
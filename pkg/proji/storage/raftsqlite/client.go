@@ -0,0 +1,129 @@
+package raftsqlite
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/nikoksr/proji/pkg/proji/storage"
+)
+
+// Client is a storage.Service that forwards every call over HTTP to a
+// raftsqlite node's Server, for a `proji` CLI that wants the shared catalog
+// without running its own Raft node.
+type Client struct {
+	baseURL   string
+	authToken string
+	http      *http.Client
+}
+
+// ClientOption configures a Client returned by NewClient.
+type ClientOption func(*Client)
+
+// WithClientAuthToken sends token as a bearer "Authorization" header on
+// every request, matching the token the node was started with via
+// raftsqlite.WithAuthToken.
+func WithClientAuthToken(token string) ClientOption {
+	return func(c *Client) { c.authToken = token }
+}
+
+// NewClient returns a storage.Service backed by the raftsqlite node's HTTP
+// API at baseURL, e.g. "http://localhost:8080".
+func NewClient(baseURL string, opts ...ClientOption) storage.Service {
+	c := &Client{baseURL: baseURL, http: http.DefaultClient}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+func (c *Client) Close() error { return nil }
+
+func (c *Client) do(method, path string, body, out interface{}) error {
+	var reqBody *bytes.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(data)
+	} else {
+		reqBody = bytes.NewReader(nil)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.authToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.authToken)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		var apiErr struct {
+			Error string `json:"error"`
+		}
+		json.NewDecoder(resp.Body).Decode(&apiErr)
+		return fmt.Errorf("%s %s: %s", method, path, apiErr.Error)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+func (c *Client) SaveClass(class *storage.Class) error {
+	return c.do(http.MethodPost, "/classes", class, nil)
+}
+
+func (c *Client) RemoveClass(name string) error {
+	return c.do(http.MethodDelete, "/classes/"+url.PathEscape(name), nil, nil)
+}
+
+func (c *Client) LoadClassByName(name string) (*storage.Class, error) {
+	var class storage.Class
+	if err := c.do(http.MethodGet, "/classes/"+url.PathEscape(name), nil, &class); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+func (c *Client) LoadClassByID(id uint) (*storage.Class, error) {
+	var class storage.Class
+	if err := c.do(http.MethodGet, fmt.Sprintf("/classes/by-id/%d", id), nil, &class); err != nil {
+		return nil, err
+	}
+	return &class, nil
+}
+
+func (c *Client) LoadClassID(name string) (uint, error) {
+	var id uint
+	err := c.do(http.MethodGet, "/classes/"+url.PathEscape(name)+"/id", nil, &id)
+	return id, err
+}
+
+func (c *Client) LoadAllClasses() ([]*storage.Class, error) {
+	var classes []*storage.Class
+	err := c.do(http.MethodGet, "/classes", nil, &classes)
+	return classes, err
+}
+
+func (c *Client) DoesLabelExist(label string) (uint, error) {
+	var id uint
+	err := c.do(http.MethodGet, "/labels/"+url.PathEscape(label), nil, &id)
+	return id, err
+}
+
+func (c *Client) TrackProject(proj *storage.Project) error {
+	return c.do(http.MethodPost, "/projects", proj, nil)
+}
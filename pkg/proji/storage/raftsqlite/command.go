@@ -0,0 +1,25 @@
+package raftsqlite
+
+import "github.com/nikoksr/proji/pkg/proji/storage"
+
+// commandKind identifies which storage.Service write method a command
+// replays against a node's local FSM.
+type commandKind string
+
+const (
+	cmdSaveClass    commandKind = "save_class"
+	cmdRemoveClass  commandKind = "remove_class"
+	cmdTrackProject commandKind = "track_project"
+)
+
+// command is the Raft log entry payload for every storage.Service write.
+// SaveClass is normally a multi-step local transaction (name, then labels,
+// folders, files, scripts); here the whole storage.Class travels as a single
+// command so one Raft Apply creates the entire class or none of it -
+// followers never observe a partially saved class.
+type command struct {
+	Kind    commandKind      `json:"kind"`
+	Class   *storage.Class   `json:"class,omitempty"`
+	Name    string           `json:"name,omitempty"`
+	Project *storage.Project `json:"project,omitempty"`
+}
@@ -0,0 +1,100 @@
+package raftsqlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hashicorp/raft"
+	"github.com/nikoksr/proji/pkg/proji/storage"
+	"github.com/nikoksr/proji/pkg/proji/storage/sqlite"
+)
+
+// fsm applies committed commands to a local sqlite-backed storage.Service.
+// Every node in the cluster runs its own fsm, kept in sync purely by
+// replaying the same command log - that's what lets reads be served from the
+// local copy while writes go through Raft.
+type fsm struct {
+	path  string
+	local storage.Service
+}
+
+func newFSM(path string) (*fsm, error) {
+	local, err := sqlite.New(path)
+	if err != nil {
+		return nil, err
+	}
+	return &fsm{path: path, local: local}, nil
+}
+
+// Apply decodes a committed command and replays it against the local
+// sqlite backend, whose own transaction/rollback handling (SaveClass's
+// cancelSave) keeps a single Apply atomic.
+func (f *fsm) Apply(log *raft.Log) interface{} {
+	var cmd command
+	if err := json.Unmarshal(log.Data, &cmd); err != nil {
+		return fmt.Errorf("decode command: %w", err)
+	}
+
+	switch cmd.Kind {
+	case cmdSaveClass:
+		return f.local.SaveClass(cmd.Class)
+	case cmdRemoveClass:
+		return f.local.RemoveClass(cmd.Name)
+	case cmdTrackProject:
+		return f.local.TrackProject(cmd.Project)
+	default:
+		return fmt.Errorf("unknown command kind %q", cmd.Kind)
+	}
+}
+
+// Snapshot dumps the local sqlite file so Raft can ship a full copy of the
+// catalog to a node that's too far behind the log to catch up by replay.
+func (f *fsm) Snapshot() (raft.FSMSnapshot, error) {
+	data, err := os.ReadFile(f.path)
+	if err != nil {
+		return nil, err
+	}
+	return &fsmSnapshot{data: data}, nil
+}
+
+// Restore replaces the local sqlite file wholesale with a snapshot taken on
+// another node.
+func (f *fsm) Restore(rc io.ReadCloser) error {
+	defer rc.Close()
+
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		return err
+	}
+
+	if err := f.local.Close(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(f.path, data, 0o600); err != nil {
+		return err
+	}
+
+	local, err := sqlite.New(f.path)
+	if err != nil {
+		return err
+	}
+	f.local = local
+	return nil
+}
+
+type fsmSnapshot struct {
+	data []byte
+}
+
+func (s *fsmSnapshot) Persist(sink raft.SnapshotSink) error {
+	if _, err := sink.Write(s.data); err != nil {
+		sink.Cancel()
+		return err
+	}
+	return sink.Close()
+}
+
+func (s *fsmSnapshot) Release() {}
@@ -0,0 +1,242 @@
+package raftsqlite
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/nikoksr/proji/pkg/proji/storage"
+)
+
+// Server exposes a Service over HTTP for Client, letting a plain `proji`
+// CLI read/write the shared class catalog without joining the Raft cluster
+// itself.
+type Server struct {
+	svc       *Service
+	authToken string
+}
+
+// ServerOption configures a Server returned by NewServer.
+type ServerOption func(*Server)
+
+// WithAuthToken requires every cluster-membership (/join) or catalog-write
+// request to carry a matching "Authorization: Bearer <token>" header. Any
+// team sharing this node over an untrusted network should set one - without
+// it, anyone who can reach the HTTP address can add themselves as a Raft
+// voter or mutate the catalog.
+func WithAuthToken(token string) ServerOption {
+	return func(s *Server) { s.authToken = token }
+}
+
+// NewServer wraps svc in an http.Handler implementing the routes Client
+// talks to.
+func NewServer(svc *Service, opts ...ServerOption) *Server {
+	s := &Server{svc: svc}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Handler returns the server's http.Handler, ready to be passed to
+// http.ListenAndServe.
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/classes", s.requireAuthForWrites(s.handleClasses))
+	mux.HandleFunc("/classes/", s.requireAuthForWrites(s.handleClassByPath))
+	mux.HandleFunc("/labels/", s.handleLabel)
+	mux.HandleFunc("/projects", s.requireAuth(s.handleTrackProject))
+	mux.HandleFunc("/join", s.requireAuth(s.handleJoin))
+	return mux
+}
+
+// requireAuth rejects the request unless it carries a bearer token matching
+// s.authToken. When no token is configured, auth is a no-op - by design, so
+// a trusted loopback/VPN-only deployment isn't forced to set one - but that
+// tradeoff is documented on the `proji serve` command.
+func (s *Server) requireAuth(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if s.authToken == "" {
+			next(w, r)
+			return
+		}
+
+		const prefix = "Bearer "
+		header := r.Header.Get("Authorization")
+		if !strings.HasPrefix(header, prefix) ||
+			subtle.ConstantTimeCompare([]byte(strings.TrimPrefix(header, prefix)), []byte(s.authToken)) != 1 {
+			writeError(w, http.StatusUnauthorized, fmt.Errorf("missing or invalid bearer token"))
+			return
+		}
+		next(w, r)
+	}
+}
+
+// requireAuthForWrites applies requireAuth only to mutating methods, so
+// reads (GET) on routes that also serve writes (POST /classes,
+// DELETE /classes/{name}) stay open to any team member while only the
+// mutating calls need the token.
+func (s *Server) requireAuthForWrites(next http.HandlerFunc) http.HandlerFunc {
+	auth := s.requireAuth(next)
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet {
+			next(w, r)
+			return
+		}
+		auth(w, r)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+func (s *Server) handleClasses(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		classes, err := s.svc.LoadAllClasses()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, classes)
+	case http.MethodPost:
+		var class storage.Class
+		if err := json.NewDecoder(r.Body).Decode(&class); err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if err := s.svc.SaveClass(&class); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, class)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleClassByPath(w http.ResponseWriter, r *http.Request) {
+	// Use the escaped path so a class name containing a literal "/" - sent
+	// by Client as "%2F" via url.PathEscape - can't be mistaken for the
+	// "by-id/" and "/id" path separators below: r.URL.Path would have
+	// already decoded "%2F" back into "/", recreating that exact collision.
+	rawRest := strings.TrimPrefix(r.URL.EscapedPath(), "/classes/")
+
+	if strings.HasPrefix(rawRest, "by-id/") {
+		id, err := strconv.ParseUint(strings.TrimPrefix(rawRest, "by-id/"), 10, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		class, err := s.svc.LoadClassByID(uint(id))
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, class)
+		return
+	}
+
+	if strings.HasSuffix(rawRest, "/id") {
+		name, err := url.PathUnescape(strings.TrimSuffix(rawRest, "/id"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		id, err := s.svc.LoadClassID(name)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, id)
+		return
+	}
+
+	name, err := url.PathUnescape(rawRest)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		class, err := s.svc.LoadClassByName(name)
+		if err != nil {
+			writeError(w, http.StatusNotFound, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, class)
+	case http.MethodDelete:
+		if err := s.svc.RemoveClass(name); err != nil {
+			writeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *Server) handleLabel(w http.ResponseWriter, r *http.Request) {
+	label, err := url.PathUnescape(strings.TrimPrefix(r.URL.EscapedPath(), "/labels/"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	id, err := s.svc.DoesLabelExist(label)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, id)
+}
+
+func (s *Server) handleTrackProject(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var proj storage.Project
+	if err := json.NewDecoder(r.Body).Decode(&proj); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.svc.TrackProject(&proj); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) handleJoin(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		NodeID string `json:"node_id"`
+		Addr   string `json:"addr"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	if err := s.svc.Join(req.NodeID, req.Addr); err != nil {
+		writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
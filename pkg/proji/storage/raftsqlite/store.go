@@ -0,0 +1,181 @@
+// Package raftsqlite implements storage.Service on top of a SQLite file
+// that's kept in sync across a team by Hashicorp Raft, following the same
+// pattern as rqlite: writes are serialized as commands and committed through
+// Raft before being applied to each node's local SQLite FSM, while reads hit
+// the local SQLite file directly.
+package raftsqlite
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"github.com/nikoksr/proji/pkg/proji/storage"
+)
+
+// Config configures a raftsqlite node.
+type Config struct {
+	NodeID       string
+	RaftBindAddr string
+	DataDir      string
+	Bootstrap    bool // bootstrap a brand new single-node cluster
+	Consistent   bool // issue a Raft barrier before each local read; only succeeds on the leader
+}
+
+// Service is a storage.Service backed by a Raft-replicated SQLite file.
+type Service struct {
+	cfg  Config
+	raft *raft.Raft
+	fsm  *fsm
+}
+
+// Open starts (or rejoins) a raftsqlite node rooted at cfg.DataDir.
+func Open(cfg Config) (*Service, error) {
+	if err := os.MkdirAll(cfg.DataDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	f, err := newFSM(filepath.Join(cfg.DataDir, "proji.sqlite3"))
+	if err != nil {
+		return nil, err
+	}
+
+	raftCfg := raft.DefaultConfig()
+	raftCfg.LocalID = raft.ServerID(cfg.NodeID)
+
+	addr, err := net.ResolveTCPAddr("tcp", cfg.RaftBindAddr)
+	if err != nil {
+		return nil, err
+	}
+	transport, err := raft.NewTCPTransport(cfg.RaftBindAddr, addr, 3, 10*time.Second, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := raft.NewFileSnapshotStore(cfg.DataDir, 2, os.Stderr)
+	if err != nil {
+		return nil, err
+	}
+
+	logStore, err := raftboltdb.NewBoltStore(filepath.Join(cfg.DataDir, "raft-log.bolt"))
+	if err != nil {
+		return nil, err
+	}
+	stableStore, err := raftboltdb.NewBoltStore(filepath.Join(cfg.DataDir, "raft-stable.bolt"))
+	if err != nil {
+		return nil, err
+	}
+
+	r, err := raft.NewRaft(raftCfg, f, logStore, stableStore, snapshots, transport)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.Bootstrap {
+		r.BootstrapCluster(raft.Configuration{
+			Servers: []raft.Server{{ID: raftCfg.LocalID, Address: transport.LocalAddr()}},
+		})
+	}
+
+	return &Service{cfg: cfg, raft: r, fsm: f}, nil
+}
+
+// Join adds a new voter to the cluster. Call it against the current leader.
+func (s *Service) Join(nodeID, addr string) error {
+	return s.raft.AddVoter(raft.ServerID(nodeID), raft.ServerAddress(addr), 0, 0).Error()
+}
+
+func (s *Service) Close() error {
+	if err := s.raft.Shutdown().Error(); err != nil {
+		return err
+	}
+	return s.fsm.local.Close()
+}
+
+func (s *Service) apply(cmd command) error {
+	data, err := json.Marshal(cmd)
+	if err != nil {
+		return err
+	}
+
+	future := s.raft.Apply(data, 10*time.Second)
+	if err := future.Error(); err != nil {
+		return err
+	}
+	if err, ok := future.Response().(error); ok && err != nil {
+		return err
+	}
+	return nil
+}
+
+// SaveClass rejects an already-existing class before the command is ever
+// submitted to Raft, rather than submitting it and rolling back afterwards:
+// the local cancelSave dance sqlite.SaveClass does on failure only runs
+// inside a single node's Apply, so a command that we already know would
+// fail must never reach the log in the first place.
+func (s *Service) SaveClass(class *storage.Class) error {
+	if _, err := s.fsm.local.LoadClassID(class.Name); err == nil {
+		return fmt.Errorf("class %s already exists", class.Name)
+	}
+	return s.apply(command{Kind: cmdSaveClass, Class: class})
+}
+
+func (s *Service) RemoveClass(name string) error {
+	return s.apply(command{Kind: cmdRemoveClass, Name: name})
+}
+
+func (s *Service) TrackProject(proj *storage.Project) error {
+	return s.apply(command{Kind: cmdTrackProject, Project: proj})
+}
+
+// barrier, when Config.Consistent is set, blocks until every command
+// committed before this call has also been applied locally, so the read that
+// follows sees all of them. Raft only accepts a barrier on the leader: on a
+// follower it returns raft's not-leader error and the read fails; reads are
+// never forwarded to the leader.
+func (s *Service) barrier() error {
+	if !s.cfg.Consistent {
+		return nil
+	}
+	return s.raft.Barrier(10 * time.Second).Error()
+}
+
+func (s *Service) LoadClassByName(name string) (*storage.Class, error) {
+	if err := s.barrier(); err != nil {
+		return nil, err
+	}
+	return s.fsm.local.LoadClassByName(name)
+}
+
+func (s *Service) LoadClassByID(id uint) (*storage.Class, error) {
+	if err := s.barrier(); err != nil {
+		return nil, err
+	}
+	return s.fsm.local.LoadClassByID(id)
+}
+
+func (s *Service) LoadClassID(name string) (uint, error) {
+	if err := s.barrier(); err != nil {
+		return 0, err
+	}
+	return s.fsm.local.LoadClassID(name)
+}
+
+func (s *Service) LoadAllClasses() ([]*storage.Class, error) {
+	if err := s.barrier(); err != nil {
+		return nil, err
+	}
+	return s.fsm.local.LoadAllClasses()
+}
+
+func (s *Service) DoesLabelExist(label string) (uint, error) {
+	if err := s.barrier(); err != nil {
+		return 0, err
+	}
+	return s.fsm.local.DoesLabelExist(label)
+}
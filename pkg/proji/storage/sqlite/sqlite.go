@@ -6,8 +6,9 @@ import (
 	"strings"
 	"time"
 
-	"github.com/mattn/go-sqlite3"
+	_ "github.com/mattn/go-sqlite3"
 	"github.com/nikoksr/proji/pkg/proji/storage"
+	"github.com/nikoksr/proji/pkg/proji/storage/migrate"
 )
 
 // Sqlite represents a sqlite connection.
@@ -28,6 +29,10 @@ func New(path string) (storage.Service, error) {
 		return nil, err
 	}
 
+	if err = migrate.Migrate(db, "sqlite"); err != nil {
+		return nil, err
+	}
+
 	return &sqlite{db, nil}, nil
 }
 
@@ -314,6 +319,11 @@ func (s *sqlite) loadFolders(class *storage.Class) error {
 	return nil
 }
 
+// loadFiles returns each class_file's template column as stored - still a
+// literal template or a "builtin://..." URI, whichever the class was saved
+// with. Resolving a builtin:// value through pkg/proji/template is not yet
+// wired into this class's file materialization and remains a required
+// follow-up, not something this storage layer does.
 func (s *sqlite) loadFiles(class *storage.Class) error {
 	query := "SELECT target, template FROM class_file WHERE class_id = ? ORDER BY target"
 
@@ -424,10 +434,8 @@ func (s *sqlite) TrackProject(proj *storage.Project) error {
 		1,
 	)
 
-	if sqliteErr, ok := err.(sqlite3.Error); ok {
-		if sqliteErr.Code == sqlite3.ErrConstraint {
-			return fmt.Errorf("project already exists")
-		}
+	if storage.IsUniqueConstraintError(err) {
+		return storage.ErrProjectExists
 	}
 	return err
 }
@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrProjectExists is returned by Service.TrackProject when a project with
+// the same unique key is already tracked.
+var ErrProjectExists = errors.New("project already exists")
+
+// IsUniqueConstraintError reports whether err was caused by a unique or
+// primary key constraint violation, independent of which backend produced
+// it. Drivers call this from TrackProject instead of type-asserting their
+// own driver error type inline.
+func IsUniqueConstraintError(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique ||
+			sqliteErr.ExtendedCode == sqlite3.ErrConstraintPrimaryKey
+	}
+
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "23505"
+	}
+
+	return false
+}
@@ -0,0 +1,84 @@
+// Package template resolves the URIs that a class_file.template row can
+// point at instead of carrying a literal template string: common boilerplate
+// - gitignores, licenses, readmes - bundled into the proji binary and
+// addressed as e.g. "builtin://gitignore/Go" or "builtin://license/MIT".
+// This is the same idea as Gitea's bundled repo config templates, with a
+// user override directory standing in for Gitea's CustomPath.
+//
+// This package only provides the resolver and the builtin:// catalog
+// (`proji template ls`); it is not yet called from wherever proji writes a
+// class's files and folders to disk during install. Wiring Resolve into
+// that materialization step is a follow-up.
+package template
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//go:embed templates
+var builtinFS embed.FS
+
+const builtinScheme = "builtin://"
+
+// IsBuiltin reports whether uri uses the builtin:// scheme this package
+// resolves.
+func IsBuiltin(uri string) bool {
+	return strings.HasPrefix(uri, builtinScheme)
+}
+
+// Resolve returns the contents of a builtin:// template URI. A user override
+// placed at $XDG_CONFIG_HOME/proji/templates/<category>/<name> takes
+// precedence over the embedded copy, so a team can restyle a builtin
+// template without forking proji.
+func Resolve(uri string) ([]byte, error) {
+	if !IsBuiltin(uri) {
+		return nil, fmt.Errorf("not a builtin template uri: %q", uri)
+	}
+	relPath := strings.TrimPrefix(uri, builtinScheme)
+	if !fs.ValidPath(relPath) {
+		return nil, fmt.Errorf("invalid builtin template uri: %q", uri)
+	}
+
+	if overridePath, err := userOverridePath(relPath); err == nil {
+		if data, err := os.ReadFile(overridePath); err == nil {
+			return data, nil
+		}
+	}
+
+	data, err := builtinFS.ReadFile(path.Join("templates", relPath))
+	if err != nil {
+		return nil, fmt.Errorf("unknown builtin template %q", uri)
+	}
+	return data, nil
+}
+
+func userOverridePath(relPath string) (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(configDir, "proji", "templates", relPath), nil
+}
+
+// List returns the builtin:// URI of every bundled template, e.g.
+// "builtin://gitignore/Go", for `proji template ls`.
+func List() ([]string, error) {
+	var uris []string
+	err := fs.WalkDir(builtinFS, "templates", func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		uris = append(uris, builtinScheme+strings.TrimPrefix(p, "templates/"))
+		return nil
+	})
+	return uris, err
+}